@@ -17,7 +17,10 @@
 package cs
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
+	"github.com/consensys/gnark-crypto/ecc"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/gkr"
 	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/polynomial"
@@ -27,7 +30,10 @@ import (
 	"github.com/consensys/gnark/constraint"
 	"github.com/consensys/gnark/std/utils/algo_utils"
 	"hash"
+	"io"
 	"math/big"
+	"math/bits"
+	"sync"
 	"time"
 )
 
@@ -36,21 +42,64 @@ type gkrSolvingData struct {
 	circuit     gkr.Circuit
 	memoryPool  polynomial.Pool
 	workers     utils.WorkerPool
+
+	// initialized is set once circuit/memoryPool/workers have been built for a
+	// given (circuit shape, nbInstances) pair, so that a gkrSolvingData kept
+	// alive across calls - whether by a closure reused across repeated solves,
+	// or by a GkrSession shared across circuits of identical shape - doesn't
+	// pay for reconversion and reallocation on every init.
+	initialized bool
+
+	// mu guards the solve-to-prove window: init rewrites d.assignments on
+	// every solve, and d.memoryPool/d.workers are likewise mutated per-solve,
+	// so two concurrent solve+prove cycles sharing this gkrSolvingData (the
+	// same circuit shape and nbInstances, as happens when it is cached in a
+	// GkrSession) would otherwise race. gkrSolveHint locks mu before touching
+	// d and gkrProveHint unlocks it once it is done reading d, so a whole
+	// solve+prove cycle runs atomically with respect to any other cycle
+	// sharing d. gkrSolveHint uses TryLock rather than Lock: nothing short of
+	// the hint-invocation framework guarantees gkrProveHint is ever called
+	// after a successful solve (e.g. the overall Solve call can abort for an
+	// unrelated reason before reaching prove), and a plain Lock left held
+	// forever in that case would wedge every future solve+prove cycle sharing
+	// d with a silent, permanent deadlock. Failing fast instead surfaces a
+	// clear error the caller can act on.
+	mu sync.Mutex
 }
 
-func convertCircuit(noPtr constraint.GkrCircuit) gkr.Circuit {
+func convertCircuit(noPtr constraint.GkrCircuit) (gkr.Circuit, error) {
 	resCircuit := make(gkr.Circuit, len(noPtr))
 	for i := range noPtr {
-		resCircuit[i].Gate = GkrGateRegistry[noPtr[i].Gate]
+		// input wires carry no gate of their own - their values come from the
+		// assignment or a dependency, never from Evaluate - so they have
+		// nothing to look up or validate the arity of.
+		if noPtr[i].IsInput() {
+			continue
+		}
+		entry, ok := lookupGateEntry(noPtr[i].Gate)
+		if !ok {
+			return nil, fmt.Errorf("gkr: gate %q is not registered; call RegisterGate before compiling this circuit", noPtr[i].Gate)
+		}
+		if entry.arity >= 0 && len(noPtr[i].Inputs) != entry.arity {
+			return nil, fmt.Errorf("gkr: wire %d: gate %q was registered with arity %d, but has %d input(s)", i, noPtr[i].Gate, entry.arity, len(noPtr[i].Inputs))
+		}
+		resCircuit[i].Gate = entry.gate
 		resCircuit[i].Inputs = algo_utils.Map(noPtr[i].Inputs, algo_utils.SlicePtrAt(resCircuit))
 	}
-	return resCircuit
+	return resCircuit, nil
 }
 
-func (d *gkrSolvingData) init(info constraint.GkrInfo) gkrAssignment {
-	d.circuit = convertCircuit(info.Circuit)
-	d.memoryPool = polynomial.NewPool(d.circuit.MemoryRequirements(info.NbInstances)...)
-	d.workers = utils.NewWorkerPool()
+func (d *gkrSolvingData) init(info constraint.GkrInfo) (gkrAssignment, error) {
+	if !d.initialized {
+		circuit, err := convertCircuit(info.Circuit)
+		if err != nil {
+			return nil, err
+		}
+		d.circuit = circuit
+		d.memoryPool = polynomial.NewPool(d.circuit.MemoryRequirements(info.NbInstances)...)
+		d.workers = utils.NewWorkerPool()
+		d.initialized = true
+	}
 
 	assignmentsSequential := make(gkrAssignment, len(d.circuit))
 	d.assignments = make(gkr.WireAssignment, len(d.circuit))
@@ -59,7 +108,7 @@ func (d *gkrSolvingData) init(info constraint.GkrInfo) gkrAssignment {
 		d.assignments[&d.circuit[i]] = assignmentsSequential[i]
 	}
 
-	return assignmentsSequential
+	return assignmentsSequential, nil
 }
 
 func (d *gkrSolvingData) dumpAssignments() {
@@ -85,18 +134,52 @@ func (a gkrAssignment) setOuts(circuit constraint.GkrCircuit, outs []*big.Int) {
 	// Check if outsI == len(outs)?
 }
 
-const log = true
+// GkrObserver lets callers observe a GKR solve/prove cycle - e.g. to emit
+// structured log events or feed metrics - instead of scraping stdout. Methods
+// are called synchronously from the hint goroutine; implementations that do
+// I/O should keep it cheap so as not to hold up the solve.
+type GkrObserver interface {
+	// OnSolveStart is called once per Solve, before any chunk is dispatched.
+	OnSolveStart(info constraint.GkrInfo)
+	// OnSolveChunk is called once per dispatched chunk of instances, right
+	// after it finishes solving, so callers can see parallelism behavior.
+	OnSolveChunk(start, end int, dur time.Duration)
+	// OnProveDone is called once Prove has produced a proof, with its
+	// flattened size in field elements.
+	OnProveDone(proofSize int, dur time.Duration)
+}
+
+// noopGkrObserver is the default GkrObserver: it observes nothing.
+type noopGkrObserver struct{}
 
-func gkrSolveHint(info constraint.GkrInfo, solvingData *gkrSolvingData) hint.Function {
+func (noopGkrObserver) OnSolveStart(constraint.GkrInfo)      {}
+func (noopGkrObserver) OnSolveChunk(int, int, time.Duration) {}
+func (noopGkrObserver) OnProveDone(int, time.Duration)       {}
+
+func gkrSolveHint(info constraint.GkrInfo, solvingData *gkrSolvingData, observer GkrObserver) hint.Function {
 	return func(_ *big.Int, ins, outs []*big.Int) error {
 
-		startTime := time.Now().UnixMicro()
+		// Locked here and unlocked by the matching gkrProveHint once it is done
+		// reading solvingData, so that a concurrent solve+prove cycle sharing
+		// this same solvingData (via a GkrSession) can't observe or clobber
+		// this cycle's assignments mid-flight; see gkrSolvingData.mu. TryLock
+		// fails fast with a clear error instead of blocking forever if a prior
+		// cycle's solve was never followed by its matching prove.
+		if !solvingData.mu.TryLock() {
+			return fmt.Errorf("gkr: solve for %s is still awaiting a matching prove from a previous cycle sharing this circuit shape", info.HashName)
+		}
+
+		observer.OnSolveStart(info)
 
 		// assumes assignmentVector is arranged wire first, instance second in order of solution
 		circuit := info.Circuit
 		nbInstances := info.NbInstances
 		offsets := info.AssignmentOffsets()
-		assignment := solvingData.init(info)
+		assignment, err := solvingData.init(info)
+		if err != nil {
+			solvingData.mu.Unlock()
+			return err
+		}
 		chunks := circuit.Chunks(nbInstances)
 
 		solveTask := func(chunkOffset int) utils.Task {
@@ -138,17 +221,14 @@ func gkrSolveHint(info constraint.GkrInfo, solvingData *gkrSolvingData) hint.Fun
 
 		start := 0
 		for _, end := range chunks {
+			chunkStart := time.Now()
 			solvingData.workers.Dispatch(end-start, 1024, solveTask(start)).Wait()
+			observer.OnSolveChunk(start, end, time.Since(chunkStart))
 			start = end
 		}
 
 		assignment.setOuts(info.Circuit, outs)
 
-		if log {
-			endTime := time.Now().UnixMicro()
-			fmt.Println("gkr proved in", endTime-startTime, "μs")
-		}
-
 		return nil
 	}
 }
@@ -159,69 +239,536 @@ func frToBigInts(dst []*big.Int, src []fr.Element) {
 	}
 }
 
-func gkrProveHint(hashName string, data *gkrSolvingData) hint.Function {
+// gkrProofMagic tags a GkrProof byte stream so ReadFrom can reject unrelated data early.
+const gkrProofMagic uint32 = 0x676b7231 // "gkr1"
+
+// GkrWireProof holds the sumcheck transcript produced for a single circuit wire:
+// the partial sum polynomials sent at each round, and - for output wires only -
+// the final evaluation proof.
+type GkrWireProof struct {
+	PartialSumPolys   [][]fr.Element
+	FinalEvalProof    []fr.Element
+	HasFinalEvalProof bool
+}
+
+// GkrProof is a portable, self-describing serialization of a GKR proof for this
+// curve. Unlike the flat []*big.Int used at the gkrProveHint boundary, a GkrProof
+// carries its own shape (per-wire, per-round polynomial lengths), so it survives
+// changes to gate degrees or circuit topology between writer and reader, and can
+// be persisted to disk or shipped over the wire.
+type GkrProof struct {
+	CurveID     ecc.ID
+	CircuitHash [32]byte
+	NbInstances int
+	HashName    string
+	Wires       []GkrWireProof
+}
+
+// newGkrProof builds a GkrProof from the raw gkr.Prove output, attaching the
+// metadata needed to later recover it from a flat []*big.Int via FromBigInts.
+func newGkrProof(info constraint.GkrInfo, proof gkr.Proof) *GkrProof {
+	p := &GkrProof{
+		CurveID:     ecc.BLS12_377,
+		CircuitHash: info.CircuitHash(),
+		NbInstances: info.NbInstances,
+		HashName:    info.HashName,
+		Wires:       make([]GkrWireProof, len(proof)),
+	}
+	for i := range proof {
+		p.Wires[i].PartialSumPolys = proof[i].PartialSumPolys
+		if proof[i].FinalEvalProof != nil {
+			p.Wires[i].FinalEvalProof = proof[i].FinalEvalProof.([]fr.Element)
+			p.Wires[i].HasFinalEvalProof = true
+		}
+	}
+	return p
+}
+
+// ToBigInts flattens p into the position-encoded []*big.Int layout expected at
+// the gkrProveHint boundary; dst must already hold at least as many entries as
+// p has field elements in total (the prove hint's output count).
+func (p *GkrProof) ToBigInts(dst []*big.Int) {
+	offset := 0
+	for i := range p.Wires {
+		for _, poly := range p.Wires[i].PartialSumPolys {
+			frToBigInts(dst[offset:], poly)
+			offset += len(poly)
+		}
+		if p.Wires[i].HasFinalEvalProof {
+			frToBigInts(dst[offset:], p.Wires[i].FinalEvalProof)
+			offset += len(p.Wires[i].FinalEvalProof)
+		}
+	}
+}
+
+// GkrProofFromBigInts is the inverse of GkrProof.ToBigInts: given the GkrInfo that
+// produced src (so the shape - sumcheck round count and polynomial degree per
+// wire - can be recovered from the circuit's gate degrees and nbInstances), it
+// rebuilds a structured GkrProof. This lets external tooling (CLIs, cross-language
+// verifiers) work from the flat hint-ABI output alone.
+func GkrProofFromBigInts(info constraint.GkrInfo, src []*big.Int) (*GkrProof, error) {
+	circuit, err := convertCircuit(info.Circuit)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &GkrProof{
+		CurveID:     ecc.BLS12_377,
+		CircuitHash: info.CircuitHash(),
+		NbInstances: info.NbInstances,
+		HashName:    info.HashName,
+		Wires:       make([]GkrWireProof, len(circuit)),
+	}
+
+	// nbRounds is the number of sumcheck rounds gnark-crypto's gkr.Prove runs per
+	// wire: one round per bit of the instance count. This mirrors the shape
+	// gkr.Prove actually produces; if that round-counting scheme ever changes,
+	// this needs to move in lockstep (ideally gnark-crypto would expose it directly).
+	nbRounds := bits.Len(uint(info.NbInstances - 1))
+
+	offset := 0
+	bigIntsToFr := func(n int) []fr.Element {
+		es := make([]fr.Element, n)
+		for i := range es {
+			es[i].SetBigInt(src[offset+i])
+		}
+		offset += n
+		return es
+	}
+
+	for i := range circuit {
+		wp := GkrWireProof{PartialSumPolys: make([][]fr.Element, 0, nbRounds)}
+		for r := 0; r < nbRounds; r++ {
+			wp.PartialSumPolys = append(wp.PartialSumPolys, bigIntsToFr(circuit[i].Gate.Degree()+1))
+		}
+		if circuit[i].IsOutput() {
+			wp.FinalEvalProof = bigIntsToFr(len(circuit[i].Inputs))
+			wp.HasFinalEvalProof = true
+		}
+		p.Wires[i] = wp
+	}
+
+	return p, nil
+}
+
+// WriteTo writes p in a binary format readable by ReadFrom: a fixed header
+// (magic, curve id, circuit hash, instance count, hash name), followed by
+// length-prefixed per-wire records of round polynomials and the optional
+// final evaluation proof, all field elements in canonical fr.Element.Marshal form.
+func (p *GkrProof) WriteTo(w io.Writer) (int64, error) {
+	buf := new(bytes.Buffer)
+
+	putUint32 := func(v uint32) {
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], v)
+		buf.Write(b[:])
+	}
+	putUint64 := func(v uint64) {
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], v)
+		buf.Write(b[:])
+	}
+	putElements := func(es []fr.Element) {
+		putUint32(uint32(len(es)))
+		for i := range es {
+			m := es[i].Marshal()
+			buf.Write(m)
+		}
+	}
+
+	putUint32(gkrProofMagic)
+	putUint32(uint32(p.CurveID))
+	buf.Write(p.CircuitHash[:])
+	putUint64(uint64(p.NbInstances))
+	putUint32(uint32(len(p.HashName)))
+	buf.WriteString(p.HashName)
+
+	putUint32(uint32(len(p.Wires)))
+	for i := range p.Wires {
+		wp := &p.Wires[i]
+		putUint32(uint32(len(wp.PartialSumPolys)))
+		for _, poly := range wp.PartialSumPolys {
+			putElements(poly)
+		}
+		if wp.HasFinalEvalProof {
+			buf.WriteByte(1)
+			putElements(wp.FinalEvalProof)
+		} else {
+			buf.WriteByte(0)
+		}
+	}
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// ReadFrom reads a GkrProof previously written by WriteTo.
+func (p *GkrProof) ReadFrom(r io.Reader) (int64, error) {
+	var total int64
+	readFull := func(b []byte) error {
+		n, err := io.ReadFull(r, b)
+		total += int64(n)
+		return err
+	}
+	readUint32 := func() (uint32, error) {
+		var b [4]byte
+		if err := readFull(b[:]); err != nil {
+			return 0, err
+		}
+		return binary.BigEndian.Uint32(b[:]), nil
+	}
+	readUint64 := func() (uint64, error) {
+		var b [8]byte
+		if err := readFull(b[:]); err != nil {
+			return 0, err
+		}
+		return binary.BigEndian.Uint64(b[:]), nil
+	}
+	readElements := func() ([]fr.Element, error) {
+		n, err := readUint32()
+		if err != nil {
+			return nil, err
+		}
+		es := make([]fr.Element, n)
+		eltBuf := make([]byte, fr.Bytes)
+		for i := range es {
+			if err := readFull(eltBuf); err != nil {
+				return nil, err
+			}
+			es[i].SetBytes(eltBuf)
+		}
+		return es, nil
+	}
+
+	magic, err := readUint32()
+	if err != nil {
+		return total, err
+	}
+	if magic != gkrProofMagic {
+		return total, fmt.Errorf("gkr: not a GkrProof stream (bad magic)")
+	}
+	curveID, err := readUint32()
+	if err != nil {
+		return total, err
+	}
+	p.CurveID = ecc.ID(curveID)
+	if err := readFull(p.CircuitHash[:]); err != nil {
+		return total, err
+	}
+	nbInstances, err := readUint64()
+	if err != nil {
+		return total, err
+	}
+	p.NbInstances = int(nbInstances)
+	hashNameLen, err := readUint32()
+	if err != nil {
+		return total, err
+	}
+	hashNameBuf := make([]byte, hashNameLen)
+	if err := readFull(hashNameBuf); err != nil {
+		return total, err
+	}
+	p.HashName = string(hashNameBuf)
+
+	nbWires, err := readUint32()
+	if err != nil {
+		return total, err
+	}
+	p.Wires = make([]GkrWireProof, nbWires)
+	for i := range p.Wires {
+		nbPolys, err := readUint32()
+		if err != nil {
+			return total, err
+		}
+		p.Wires[i].PartialSumPolys = make([][]fr.Element, nbPolys)
+		for j := range p.Wires[i].PartialSumPolys {
+			poly, err := readElements()
+			if err != nil {
+				return total, err
+			}
+			p.Wires[i].PartialSumPolys[j] = poly
+		}
+		var hasFinal [1]byte
+		if err := readFull(hasFinal[:]); err != nil {
+			return total, err
+		}
+		if hasFinal[0] == 1 {
+			fe, err := readElements()
+			if err != nil {
+				return total, err
+			}
+			p.Wires[i].FinalEvalProof = fe
+			p.Wires[i].HasFinalEvalProof = true
+		}
+	}
+	return total, nil
+}
+
+func gkrProveHint(info constraint.GkrInfo, data *gkrSolvingData, observer GkrObserver) hint.Function {
 
 	return func(_ *big.Int, ins, outs []*big.Int) error {
 
-		startTime := time.Now().UnixMicro()
+		// Unlocks the mu locked by the matching gkrSolveHint, on every return
+		// path, once this cycle is done reading data; see gkrSolvingData.mu.
+		defer data.mu.Unlock()
+
+		startTime := time.Now()
 
 		insBytes := algo_utils.Map(ins[1:], func(i *big.Int) []byte { // the first input is dummy, just to ensure the solver's work is done before the prover is called
 			b := i.Bytes()
 			return b[:]
 		})
 
-		hsh := HashBuilderRegistry[hashName]()
+		hashBuilder, ok := LookupHashBuilder(info.HashName)
+		if !ok {
+			return fmt.Errorf("gkr: hash %q is not registered; call RegisterHashBuilder before proving", info.HashName)
+		}
+		hsh := hashBuilder()
 
-		proof, err := gkr.Prove(data.circuit, data.assignments, fiatshamir.WithHash(hsh, insBytes...), gkr.WithPool(&data.memoryPool), gkr.WithWorkers(&data.workers))
+		rawProof, err := gkr.Prove(data.circuit, data.assignments, fiatshamir.WithHash(hsh, insBytes...), gkr.WithPool(&data.memoryPool), gkr.WithWorkers(&data.workers))
 		if err != nil {
 			return err
 		}
 
-		// serialize proof: TODO: In gnark-crypto?
-		offset := 0
-		for i := range proof {
-			for _, poly := range proof[i].PartialSumPolys {
-				frToBigInts(outs[offset:], poly)
-				offset += len(poly)
-			}
-			if proof[i].FinalEvalProof != nil {
-				finalEvalProof := proof[i].FinalEvalProof.([]fr.Element)
-				frToBigInts(outs[offset:], finalEvalProof)
-				offset += len(finalEvalProof)
-			}
-		}
+		proof := newGkrProof(info, rawProof)
+		proof.ToBigInts(outs)
 
 		data.dumpAssignments()
 
-		endTime := time.Now().UnixMicro()
-		fmt.Println("gkr solved in", endTime-startTime, "μs")
+		observer.OnProveDone(len(outs), time.Since(startTime))
 
 		return nil
 
 	}
 }
 
-func defineGkrHints(info constraint.GkrInfo, hintFunctions map[hint.ID]hint.Function) map[hint.ID]hint.Function {
+// GkrOption configures the behavior of defineGkrHints; see WithGkrSession and
+// WithGkrObserver.
+type GkrOption func(*gkrOptions)
+
+type gkrOptions struct {
+	session  *GkrSession
+	observer GkrObserver
+}
+
+// WithGkrSession makes defineGkrHints acquire (and share) the gkrSolvingData
+// backing its hints from session instead of allocating a fresh one; see GkrSession.
+func WithGkrSession(session *GkrSession) GkrOption {
+	return func(o *gkrOptions) { o.session = session }
+}
+
+// WithGkrObserver makes the solve/prove hints report their progress and timings
+// to observer instead of doing nothing. See GkrObserver.
+func WithGkrObserver(observer GkrObserver) GkrOption {
+	return func(o *gkrOptions) { o.observer = observer }
+}
+
+// defineGkrHints wires info's solve/prove hints into hintFunctions. By default
+// it allocates a fresh gkrSolvingData and reports nothing; see WithGkrSession
+// and WithGkrObserver to change either.
+func defineGkrHints(info constraint.GkrInfo, hintFunctions map[hint.ID]hint.Function, opts ...GkrOption) map[hint.ID]hint.Function {
+	o := gkrOptions{observer: noopGkrObserver{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	res := make(map[hint.ID]hint.Function, len(hintFunctions)+2)
 	for k, v := range hintFunctions {
 		res[k] = v
 	}
 
-	var gkrData gkrSolvingData
-	res[info.SolveHintID] = gkrSolveHint(info, &gkrData)
-	res[info.ProveHintID] = gkrProveHint(info.HashName, &gkrData)
+	var gkrData *gkrSolvingData
+	if o.session != nil {
+		gkrData = o.session.Acquire(info)
+	} else {
+		gkrData = new(gkrSolvingData)
+	}
+	res[info.SolveHintID] = gkrSolveHint(info, gkrData, o.observer)
+	res[info.ProveHintID] = gkrProveHint(info, gkrData, o.observer)
 	return res
 }
 
-var GkrGateRegistry = map[string]gkr.Gate{ // TODO: Migrate to gnark-crypto
-	"mul": mulGate(2),
-	"add": addGate{},
-	"sub": subGate{},
-	"neg": negGate{},
+// gkrSessionKey identifies a GKR sub-circuit shape: its topology, keyed by
+// constraint.GkrInfo.CircuitHash, bucketed by the instance count it is solved
+// at (the memory pool and chunking depend on nbInstances, not just topology).
+type gkrSessionKey struct {
+	circuitHash [32]byte
+	nbInstances int
 }
 
-// TODO: Move to gnark-crypto
-var HashBuilderRegistry = make(map[string]func() hash.Hash)
+// GkrSession keeps the converted gkr.Circuit, sized polynomial.Pool and shared
+// utils.WorkerPool for a GKR sub-circuit shape alive across calls to
+// defineGkrHints, instead of reallocating them on every Solve+Prove cycle.
+// This amortizes conversion and allocation cost for workloads that prove the
+// same GKR sub-circuit many times, e.g. batching or aggregation pipelines that
+// repeatedly solve an identical inner circuit. Acquire/Release themselves are
+// safe for concurrent use (they only touch the session's own bookkeeping),
+// but the gkrSolvingData a shape maps to is not: while one solve+prove cycle
+// for a shape is in flight, a solve for the same shape fails with an error
+// (gkrSolvingData.mu is taken with TryLock) instead of waiting for it.
+// Acquire/Release play no part in this.
+type GkrSession struct {
+	mu   sync.Mutex
+	data map[gkrSessionKey]*gkrSolvingData
+}
+
+// NewGkrSession returns an empty GkrSession.
+func NewGkrSession() *GkrSession {
+	return &GkrSession{data: make(map[gkrSessionKey]*gkrSolvingData)}
+}
+
+// Acquire returns the gkrSolvingData cached for info's circuit shape and
+// instance count, allocating one on first use of that shape.
+func (s *GkrSession) Acquire(info constraint.GkrInfo) *gkrSolvingData {
+	key := gkrSessionKey{circuitHash: info.CircuitHash(), nbInstances: info.NbInstances}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.data[key]
+	if !ok {
+		d = new(gkrSolvingData)
+		s.data[key] = d
+	}
+	return d
+}
+
+// Release is currently a no-op: data stays in the session, indexed by its
+// shape, so that the next Acquire for the same shape reuses it; callers that
+// want to free a shape's resources should discard the whole GkrSession
+// instead. It exists so callers can pair it with Acquire without relying on
+// that implementation detail, in case a future GkrSession eviction policy
+// needs it to do real work.
+func (s *GkrSession) Release(data *gkrSolvingData) {}
+
+// gkrGateEntry pairs a registered gate with the degree and arity it was
+// declared under, so that a mismatching Degree() implementation, or a
+// constraint.GkrCircuit wire feeding it the wrong number of inputs, is caught
+// rather than silently misevaluated or passed to a panicking Evaluate.
+type gkrGateEntry struct {
+	gate   gkr.Gate
+	degree int
+	arity  int // number of inputs the gate expects; -1 if variadic/unchecked
+}
+
+var (
+	gkrRegistryMu sync.RWMutex
+	gkrGates      = map[string]gkrGateEntry{ // TODO: Migrate to gnark-crypto
+		"mul": {mulGate(2), 2, 2},
+		"add": {addGate{}, 1, 2},
+		"sub": {subGate{}, 1, 2},
+		"neg": {negGate{}, 1, 1},
+	}
+
+	hashBuildersMu sync.RWMutex
+	hashBuilders   = make(map[string]func() hash.Hash) // TODO: Move to gnark-crypto
+)
+
+// RegisterGate registers a gate under name so that a constraint.GkrCircuit wire
+// referencing name can be converted to a gkr.Circuit. degree must match
+// g.Degree(). arity is the number of inputs the gate expects; a wire
+// referencing name with a different number of inputs is rejected by
+// convertCircuit instead of being passed through to Evaluate. Pass a negative
+// arity for a gate that genuinely accepts a variable number of inputs, to
+// opt out of that check.
+// RegisterGate fails if name is already registered; use ReplaceGate to override
+// an existing entry deliberately.
+func RegisterGate(name string, g gkr.Gate, degree, arity int) error {
+	gkrRegistryMu.Lock()
+	defer gkrRegistryMu.Unlock()
+	if _, ok := gkrGates[name]; ok {
+		return fmt.Errorf("gkr: gate %q is already registered; use ReplaceGate to override it", name)
+	}
+	return registerGateLocked(name, g, degree, arity)
+}
+
+// ReplaceGate behaves like RegisterGate, but overrides name if it is already registered.
+func ReplaceGate(name string, g gkr.Gate, degree, arity int) error {
+	gkrRegistryMu.Lock()
+	defer gkrRegistryMu.Unlock()
+	return registerGateLocked(name, g, degree, arity)
+}
+
+func registerGateLocked(name string, g gkr.Gate, degree, arity int) error {
+	if g == nil {
+		return fmt.Errorf("gkr: gate %q: nil gate", name)
+	}
+	if degree < 0 {
+		return fmt.Errorf("gkr: gate %q: negative degree %d", name, degree)
+	}
+	if gd := g.Degree(); gd != degree {
+		return fmt.Errorf("gkr: gate %q: declared degree %d does not match g.Degree() %d", name, degree, gd)
+	}
+	gkrGates[name] = gkrGateEntry{gate: g, degree: degree, arity: arity}
+	return nil
+}
+
+// LookupGate returns the gate registered under name, and whether it was found.
+func LookupGate(name string) (gkr.Gate, bool) {
+	gkrRegistryMu.RLock()
+	defer gkrRegistryMu.RUnlock()
+	e, ok := gkrGates[name]
+	if !ok {
+		return nil, false
+	}
+	return e.gate, true
+}
+
+// lookupGateEntry is LookupGate plus the declared arity, for convertCircuit's
+// use in validating a constraint.GkrCircuit wire's input count before it
+// reaches the gate's Evaluate.
+func lookupGateEntry(name string) (gkrGateEntry, bool) {
+	gkrRegistryMu.RLock()
+	defer gkrRegistryMu.RUnlock()
+	e, ok := gkrGates[name]
+	return e, ok
+}
+
+// ListGates returns the names of all currently registered gates, in no particular order.
+func ListGates() []string {
+	gkrRegistryMu.RLock()
+	defer gkrRegistryMu.RUnlock()
+	names := make([]string, 0, len(gkrGates))
+	for name := range gkrGates {
+		names = append(names, name)
+	}
+	return names
+}
+
+// RegisterHashBuilder registers a hash.Hash constructor under name so that a
+// constraint.GkrInfo.HashName referencing name can be used by gkrProveHint.
+// RegisterHashBuilder fails if name is already registered; use ReplaceHashBuilder
+// to override an existing entry deliberately.
+func RegisterHashBuilder(name string, builder func() hash.Hash) error {
+	hashBuildersMu.Lock()
+	defer hashBuildersMu.Unlock()
+	if _, ok := hashBuilders[name]; ok {
+		return fmt.Errorf("gkr: hash %q is already registered; use ReplaceHashBuilder to override it", name)
+	}
+	return replaceHashBuilderLocked(name, builder)
+}
+
+// ReplaceHashBuilder behaves like RegisterHashBuilder, but overrides name if it is already registered.
+func ReplaceHashBuilder(name string, builder func() hash.Hash) error {
+	hashBuildersMu.Lock()
+	defer hashBuildersMu.Unlock()
+	return replaceHashBuilderLocked(name, builder)
+}
+
+func replaceHashBuilderLocked(name string, builder func() hash.Hash) error {
+	if builder == nil {
+		return fmt.Errorf("gkr: hash %q: nil builder", name)
+	}
+	hashBuilders[name] = builder
+	return nil
+}
+
+// LookupHashBuilder returns the hash.Hash constructor registered under name, and whether it was found.
+func LookupHashBuilder(name string) (func() hash.Hash, bool) {
+	hashBuildersMu.RLock()
+	defer hashBuildersMu.RUnlock()
+	b, ok := hashBuilders[name]
+	return b, ok
+}
 
 type mulGate int
 type addGate struct{}
@@ -0,0 +1,67 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cs
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark/constraint"
+)
+
+// benchGkrInfo returns a trivial one-gate GKR circuit (two input wires feeding
+// an "add" gate) at the given instance count, just large enough to exercise
+// gkrSolvingData.init's circuit conversion and memory pool sizing. Wires 0 and
+// 1 are plain input wires (no Gate, no Inputs) - convertCircuit skips gate
+// lookup and arity validation for those, same as a real compiled circuit.
+func benchGkrInfo(nbInstances int) constraint.GkrInfo {
+	return constraint.GkrInfo{
+		Circuit: constraint.GkrCircuit{
+			{},
+			{},
+			{Gate: "add", Inputs: []int{0, 1}},
+		},
+		NbInstances: nbInstances,
+		MaxNIns:     2,
+	}
+}
+
+// BenchmarkGkrSolvingDataInit_Fresh initializes the same shape repeatedly with
+// a brand new gkrSolvingData every time, so every call pays for converting the
+// circuit and sizing a new memory pool - the cost GkrSession exists to avoid.
+func BenchmarkGkrSolvingDataInit_Fresh(b *testing.B) {
+	info := benchGkrInfo(1 << 10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d := new(gkrSolvingData)
+		if _, err := d.init(info); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkGkrSolvingDataInit_Reused reuses one gkrSolvingData across every
+// iteration, as a GkrSession does across repeated Solve+Prove cycles of the
+// same circuit shape: circuit conversion and pool/worker allocation happen
+// once, and every later init only re-sizes the per-solve assignment vectors.
+func BenchmarkGkrSolvingDataInit_Reused(b *testing.B) {
+	info := benchGkrInfo(1 << 10)
+	d := new(gkrSolvingData)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := d.init(info); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gkrobserver
+
+import (
+	"time"
+
+	"github.com/consensys/gnark/constraint"
+	bls12377cs "github.com/consensys/gnark/constraint/bls12-377"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Prometheus is a cs.GkrObserver that records solve/prove activity as
+// Prometheus counters and histograms, so a GKR-heavy service can expose them
+// alongside its other metrics instead of relying on log scraping.
+type Prometheus struct {
+	SolveStarts prometheus.Counter
+	SolveChunks prometheus.Histogram
+	ProveDones  prometheus.Histogram
+	ProofSizes  prometheus.Histogram
+}
+
+// NewPrometheus creates a Prometheus observer with metrics registered on reg
+// under the "gnark_gkr" namespace.
+func NewPrometheus(reg prometheus.Registerer) *Prometheus {
+	p := &Prometheus{
+		SolveStarts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "gnark_gkr",
+			Name:      "solve_starts_total",
+			Help:      "Number of GKR solve hint invocations.",
+		}),
+		SolveChunks: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "gnark_gkr",
+			Name:      "solve_chunk_duration_seconds",
+			Help:      "Duration of each dispatched GKR solve chunk.",
+		}),
+		ProveDones: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "gnark_gkr",
+			Name:      "prove_duration_seconds",
+			Help:      "Duration of a full GKR prove call.",
+		}),
+		ProofSizes: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "gnark_gkr",
+			Name:      "proof_size_field_elements",
+			Help:      "Flattened size of produced GKR proofs, in field elements.",
+		}),
+	}
+	reg.MustRegister(p.SolveStarts, p.SolveChunks, p.ProveDones, p.ProofSizes)
+	return p
+}
+
+func (p *Prometheus) OnSolveStart(constraint.GkrInfo) {
+	p.SolveStarts.Inc()
+}
+
+func (p *Prometheus) OnSolveChunk(_, _ int, dur time.Duration) {
+	p.SolveChunks.Observe(dur.Seconds())
+}
+
+func (p *Prometheus) OnProveDone(proofSize int, dur time.Duration) {
+	p.ProveDones.Observe(dur.Seconds())
+	p.ProofSizes.Observe(float64(proofSize))
+}
+
+var _ bls12377cs.GkrObserver = (*Prometheus)(nil)
@@ -0,0 +1,53 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gkrobserver provides cs.GkrObserver implementations so that GKR
+// solve/prove hints can be wired into a caller's logging and metrics stack
+// instead of scraping stdout.
+package gkrobserver
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/consensys/gnark/constraint"
+	bls12377cs "github.com/consensys/gnark/constraint/bls12-377"
+)
+
+// Slog is a cs.GkrObserver that emits structured events through a *slog.Logger.
+// The zero value logs to slog's default logger.
+type Slog struct {
+	Logger *slog.Logger
+}
+
+func (s Slog) logger() *slog.Logger {
+	if s.Logger != nil {
+		return s.Logger
+	}
+	return slog.Default()
+}
+
+func (s Slog) OnSolveStart(info constraint.GkrInfo) {
+	s.logger().Info("gkr solve start", "nbInstances", info.NbInstances, "hashName", info.HashName)
+}
+
+func (s Slog) OnSolveChunk(start, end int, dur time.Duration) {
+	s.logger().Info("gkr solve chunk", "start", start, "end", end, "duration", dur)
+}
+
+func (s Slog) OnProveDone(proofSize int, dur time.Duration) {
+	s.logger().Info("gkr prove done", "proofSize", proofSize, "duration", dur)
+}
+
+var _ bls12377cs.GkrObserver = Slog{}
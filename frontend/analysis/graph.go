@@ -0,0 +1,89 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package analysis exposes a region-level call/dependency graph for a
+// compiled circuit, built from the regions recorded through
+// frontend.BeginRegion/EndRegion, so that large circuits can be profiled at a
+// coarser grain than the flat per-circuit constraint and coefficient counts.
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Node aggregates the constraints and hints emitted under a single region
+// path (e.g. "poseidon/round3"). The root region, outside of any
+// BeginRegion/EndRegion pair, has an empty Region.
+type Node struct {
+	Region        string
+	NbConstraints int
+	NbHints       int
+	NbTerms       int // total number of L/R/O terms across the region's constraints
+}
+
+// AvgTermSize returns the average number of L/R/O terms per constraint in the
+// node, or 0 if the node has no constraints.
+func (n Node) AvgTermSize() float64 {
+	if n.NbConstraints == 0 {
+		return 0
+	}
+	return float64(n.NbTerms) / float64(n.NbConstraints)
+}
+
+// Edge records that a constraint in To consumes a wire produced by a
+// constraint or hint in From, weighted by how many distinct wires are shared.
+type Edge struct {
+	From, To string
+	Weight   int
+}
+
+// Graph is a region-level call/dependency graph: nodes are regions, edges
+// capture data flow between them through shared internal wires.
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+}
+
+// WriteDOT writes g in Graphviz DOT format, so it can be rendered directly
+// with `dot -Tpng` to see which regions dominate constraint count.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "digraph circuit {"); err != nil {
+		return err
+	}
+	for _, n := range g.Nodes {
+		label := n.Region
+		if label == "" {
+			label = "(root)"
+		}
+		if _, err := fmt.Fprintf(w, "  %q [label=%q, constraints=%d, hints=%d];\n", n.Region, label, n.NbConstraints, n.NbHints); err != nil {
+			return err
+		}
+	}
+	for _, e := range g.Edges {
+		if _, err := fmt.Fprintf(w, "  %q -> %q [weight=%d];\n", e.From, e.To, e.Weight); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+// WriteJSON writes g as JSON, for tooling that would rather not parse DOT.
+func (g *Graph) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(g)
+}
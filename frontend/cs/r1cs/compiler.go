@@ -31,6 +31,7 @@ import (
 	"github.com/consensys/gnark/backend"
 	"github.com/consensys/gnark/backend/hint"
 	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/analysis"
 	"github.com/consensys/gnark/frontend/compiled"
 	"github.com/consensys/gnark/frontend/cs"
 	"github.com/consensys/gnark/frontend/schema"
@@ -57,6 +58,32 @@ type compiler struct {
 
 	// map for recording boolean constrained variables (to not constrain them twice)
 	mtBooleans map[uint64][]compiled.LinearExpression
+
+	// cseTable memoizes, per LinExp.HashCode(), the linear expressions already
+	// materialized into an internal wire (see getOrCreateLinearExpressionVariable),
+	// so that identical sub-expressions built at different call sites share a
+	// wire instead of each allocating their own.
+	cseTable map[uint64][]linExpEntry
+	// cseInvalidated holds internal wire IDs that used to back a cseTable entry
+	// but have since been specialized (e.g. boolean-constrained) and so must no
+	// longer be handed out as a plain linear combination.
+	cseInvalidated map[int]bool
+	// cseHits counts how many times getOrCreateLinearExpressionVariable returned
+	// a memoized wire instead of adding a new constraint.
+	cseHits int
+
+	// regionStack holds the names pushed by BeginRegion, innermost last.
+	regionStack []string
+	// regions[i] is the region path (regionStack joined by "/" at the time)
+	// under which cs.Constraints[i] was added; kept in lockstep with
+	// Constraints by every pass that filters or reorders it.
+	regions []string
+}
+
+// linExpEntry is a single memoized entry in compiler.cseTable.
+type linExpEntry struct {
+	le compiled.LinearExpression
+	v  compiled.Variable
 }
 
 // initialCapacity has quite some impact on frontend performance, especially on large circuits size
@@ -68,10 +95,13 @@ func newCompiler(curveID ecc.ID, config frontend.CompileConfig) *compiler {
 			MDebug: make(map[int]int),
 			MHints: make(map[int]*compiled.Hint),
 		},
-		Constraints: make([]compiled.R1C, 0, config.Capacity),
-		st:          cs.NewCoeffTable(),
-		mtBooleans:  make(map[uint64][]compiled.LinearExpression),
-		config:      config,
+		Constraints:    make([]compiled.R1C, 0, config.Capacity),
+		regions:        make([]string, 0, config.Capacity),
+		st:             cs.NewCoeffTable(),
+		mtBooleans:     make(map[uint64][]compiled.LinearExpression),
+		cseTable:       make(map[uint64][]linExpEntry),
+		cseInvalidated: make(map[int]bool),
+		config:         config,
 	}
 
 	system.Public = make([]string, 1)
@@ -95,6 +125,159 @@ func (system *compiler) newInternalVariable() compiled.Variable {
 	}
 }
 
+// getOrCreateLinearExpressionVariable returns the internal wire that equals
+// le, reusing one already materialized for an equal linear combination if
+// system.config.EnableCSE is set, instead of always allocating a fresh wire
+// and constraint. It's meant as the entry point an API-layer helper (e.g. one
+// backing Add/Mul) would call when it needs a single variable to stand in for
+// a linear combination of existing variables, as opposed to
+// newInternalVariable, which always allocates - no such caller exists in this
+// snapshot yet, so EnableCSE currently has no effect on any compiled circuit.
+// NewHint does not route through it: hint Inputs already accept a
+// compiled.LinearExpression directly, so there's nothing to materialize.
+func (system *compiler) getOrCreateLinearExpressionVariable(le compiled.Variable) compiled.Variable {
+	if !system.config.EnableCSE {
+		return system.newLinearExpressionVariable(le)
+	}
+
+	l := le.LinExp
+	if !sort.IsSorted(l) {
+		sort.Sort(l)
+	}
+
+	key := l.HashCode()
+	for _, e := range system.cseTable[key] {
+		if !e.le.Equal(l) {
+			continue
+		}
+		_, wID, wVis := e.v.LinExp[0].Unpack()
+		if wVis == schema.Internal && system.cseInvalidated[wID] {
+			continue
+		}
+		system.cseHits++
+		return e.v
+	}
+
+	v := system.newLinearExpressionVariable(le)
+	lCopy := make(compiled.LinearExpression, len(l))
+	copy(lCopy, l)
+	system.cseTable[key] = append(system.cseTable[key], linExpEntry{le: lCopy, v: v})
+	return v
+}
+
+// newLinearExpressionVariable allocates a fresh internal wire constrained to
+// equal le (1 * le == w), without consulting or populating the CSE table.
+func (system *compiler) newLinearExpressionVariable(le compiled.Variable) compiled.Variable {
+	w := system.newInternalVariable()
+	system.addConstraint(newR1C(system.one(), le, w))
+	return w
+}
+
+// CSEHits returns how many times getOrCreateLinearExpressionVariable returned
+// a memoized wire instead of adding a new constraint. It will read zero until
+// some API-layer caller routes through getOrCreateLinearExpressionVariable.
+func (system *compiler) CSEHits() int {
+	return system.cseHits
+}
+
+// CallGraph aggregates the regions recorded via BeginRegion/EndRegion into a
+// region-level call/dependency graph: nodes are regions, with their
+// constraint, hint and term counts; edges record that a region's constraints
+// consume a wire produced under another region, weighted by how many such
+// wires are shared.
+//
+// It reads compiled.Hint.Region (set by NewHint via currentRegion) alongside
+// cs.regions; like the fold/eliminate fields noted in Compile, that field
+// presupposes a compiled package definition this tree doesn't vendor, which
+// predates this addition rather than being introduced by it.
+func (system *compiler) CallGraph() *analysis.Graph {
+	var g analysis.Graph
+	nodeIdx := make(map[string]int)
+	nodeFor := func(region string) int {
+		if idx, ok := nodeIdx[region]; ok {
+			return idx
+		}
+		idx := len(g.Nodes)
+		nodeIdx[region] = idx
+		g.Nodes = append(g.Nodes, analysis.Node{Region: region})
+		return idx
+	}
+
+	// producerRegion[wID] is the region that first produced internal wire wID.
+	producerRegion := make(map[int]string, system.NbInternalVariables)
+	for cID, r1c := range system.Constraints {
+		region := system.regions[cID]
+		idx := nodeFor(region)
+		g.Nodes[idx].NbConstraints++
+		g.Nodes[idx].NbTerms += len(r1c.L.LinExp) + len(r1c.R.LinExp) + len(r1c.O.LinExp)
+		for _, t := range r1c.O.LinExp {
+			if t.CoeffID() == compiled.CoeffIdZero || t.VariableVisibility() != schema.Internal {
+				continue
+			}
+			if _, ok := producerRegion[t.WireID()]; !ok {
+				producerRegion[t.WireID()] = region
+			}
+		}
+	}
+	seenHints := make(map[*compiled.Hint]bool, len(system.MHints))
+	for _, h := range system.MHints {
+		if seenHints[h] {
+			continue
+		}
+		seenHints[h] = true
+		idx := nodeFor(h.Region)
+		g.Nodes[idx].NbHints++
+		for _, wID := range h.Wires {
+			if _, ok := producerRegion[wID]; !ok {
+				producerRegion[wID] = h.Region
+			}
+		}
+	}
+
+	edgeWeight := make(map[[2]string]int)
+	// counted makes each shared wire add to an edge's weight only once.
+	type edgeWire struct {
+		from, to string
+		wID      int
+	}
+	counted := make(map[edgeWire]bool)
+	mark := func(region string, l compiled.LinearExpression) {
+		for _, t := range l {
+			if t.CoeffID() == compiled.CoeffIdZero || t.VariableVisibility() != schema.Internal {
+				continue
+			}
+			from, ok := producerRegion[t.WireID()]
+			if !ok || from == region {
+				continue
+			}
+			ew := edgeWire{from: from, to: region, wID: t.WireID()}
+			if counted[ew] {
+				continue
+			}
+			counted[ew] = true
+			edgeWeight[[2]string{from, region}]++
+		}
+	}
+	for cID, r1c := range system.Constraints {
+		region := system.regions[cID]
+		mark(region, r1c.L.LinExp)
+		mark(region, r1c.R.LinExp)
+		mark(region, r1c.O.LinExp)
+	}
+
+	for key, w := range edgeWeight {
+		g.Edges = append(g.Edges, analysis.Edge{From: key[0], To: key[1], Weight: w})
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i].From != g.Edges[j].From {
+			return g.Edges[i].From < g.Edges[j].From
+		}
+		return g.Edges[i].To < g.Edges[j].To
+	})
+
+	return &g
+}
+
 // AddPublicVariable creates a new public Variable
 func (system *compiler) AddPublicVariable(name string) frontend.Variable {
 	if system.Schema != nil {
@@ -176,11 +359,34 @@ func newR1C(_l, _r, _o frontend.Variable) compiled.R1C {
 
 func (system *compiler) addConstraint(r1c compiled.R1C, debugID ...int) {
 	system.Constraints = append(system.Constraints, r1c)
+	system.regions = append(system.regions, system.currentRegion())
 	if len(debugID) > 0 {
 		system.MDebug[len(system.Constraints)-1] = debugID[0]
 	}
 }
 
+// BeginRegion pushes name onto the region stack; every constraint and hint
+// added before the matching EndRegion is tagged with the resulting path
+// (nested region names joined by "/"), so CallGraph can attribute constraint
+// and hint counts to the part of the circuit that produced them.
+func (system *compiler) BeginRegion(name string) {
+	system.regionStack = append(system.regionStack, name)
+}
+
+// EndRegion pops the region pushed by the matching BeginRegion.
+func (system *compiler) EndRegion() {
+	if len(system.regionStack) == 0 {
+		panic("EndRegion called without a matching BeginRegion")
+	}
+	system.regionStack = system.regionStack[:len(system.regionStack)-1]
+}
+
+// currentRegion returns the region path new constraints and hints should be
+// tagged with, the empty string if no BeginRegion is currently open.
+func (system *compiler) currentRegion() string {
+	return strings.Join(system.regionStack, "/")
+}
+
 // Term packs a Variable and a coeff in a Term and returns it.
 // func (system *R1CSRefactor) setCoeff(v Variable, coeff *big.Int) Term {
 func (system *compiler) setCoeff(v compiled.Term, coeff *big.Int) compiled.Term {
@@ -208,6 +414,12 @@ func (system *compiler) MarkBoolean(v frontend.Variable) {
 	list := system.mtBooleans[key]
 	list = append(list, l)
 	system.mtBooleans[key] = list
+
+	// a boolean-constrained wire has been specialized beyond "equals this linear
+	// combination"; it must no longer be handed out by the CSE table.
+	if len(l) == 1 && l[0].VariableVisibility() == schema.Internal {
+		system.cseInvalidated[l[0].WireID()] = true
+	}
 }
 
 // IsBoolean returns true if given variable was marked as boolean in the compiler (see MarkBoolean)
@@ -338,6 +550,411 @@ func (system *compiler) checkVariables() error {
 	return errors.New(sbb.String())
 }
 
+// wireProducer records which constraint or hint produces a given internal wire,
+// mirroring the "who solves this wire" bookkeeping buildLevels relies on.
+type wireProducer struct {
+	constraintID int
+	hint         *compiled.Hint
+}
+
+// eliminateDeadConstraints runs a backward liveness dataflow over cs.Constraints
+// and drops constraints that contribute nothing to any public or secret wire.
+// A wire is live if it is public or secret, or if it is referenced by a live
+// constraint (directly, or - for a wire produced by a hint - through that
+// hint's inputs). A constraint is live if any non-zero term of its L, R or O
+// references a live wire. Constraints left with no live terms, including
+// tautological 0 == 0 R1Cs, are dropped, and internal wire IDs are compacted to
+// close the resulting gaps. It returns the number of constraints and internal
+// wires removed.
+func (cs *compiler) eliminateDeadConstraints() (droppedConstraints, droppedWires int) {
+	producers := make(map[int]wireProducer, cs.NbInternalVariables)
+	for cID, r1c := range cs.Constraints {
+		for _, t := range r1c.O.LinExp {
+			if t.CoeffID() == compiled.CoeffIdZero || t.VariableVisibility() != schema.Internal {
+				continue
+			}
+			if _, ok := producers[t.WireID()]; !ok {
+				producers[t.WireID()] = wireProducer{constraintID: cID}
+			}
+		}
+	}
+	for vID, h := range cs.MHints {
+		if _, ok := producers[vID]; !ok {
+			producers[vID] = wireProducer{constraintID: -1, hint: h}
+		}
+	}
+
+	live := make([]bool, len(cs.Constraints))
+	liveHints := make(map[*compiled.Hint]bool, len(cs.MHints))
+
+	var markConstraint func(cID int)
+
+	markWire := func(t compiled.Term) {
+		if t.CoeffID() == compiled.CoeffIdZero || t.VariableVisibility() != schema.Internal {
+			return
+		}
+		p, ok := producers[t.WireID()]
+		if !ok {
+			return
+		}
+		if p.hint != nil {
+			if liveHints[p.hint] {
+				return
+			}
+			liveHints[p.hint] = true
+			for _, in := range p.hint.Inputs {
+				switch t := in.(type) {
+				case compiled.Variable:
+					for _, it := range t.LinExp {
+						markWire(it)
+					}
+				case compiled.LinearExpression:
+					for _, it := range t {
+						markWire(it)
+					}
+				}
+			}
+			return
+		}
+		markConstraint(p.constraintID)
+	}
+
+	markConstraint = func(cID int) {
+		if live[cID] {
+			return
+		}
+		live[cID] = true
+		r1c := cs.Constraints[cID]
+		for _, t := range r1c.L.LinExp {
+			markWire(t)
+		}
+		for _, t := range r1c.R.LinExp {
+			markWire(t)
+		}
+		for _, t := range r1c.O.LinExp {
+			markWire(t)
+		}
+	}
+
+	// roots: a constraint is live on its own if it touches a public or secret
+	// wire, since those are observable outside the circuit.
+	referencesExternal := func(l compiled.LinearExpression) bool {
+		for _, t := range l {
+			if t.CoeffID() != compiled.CoeffIdZero && t.VariableVisibility() != schema.Internal {
+				return true
+			}
+		}
+		return false
+	}
+	isZero := func(l compiled.LinearExpression) bool {
+		for _, t := range l {
+			if t.CoeffID() != compiled.CoeffIdZero {
+				return false
+			}
+		}
+		return true
+	}
+	for cID, r1c := range cs.Constraints {
+		if live[cID] {
+			continue
+		}
+		if referencesExternal(r1c.L.LinExp) || referencesExternal(r1c.R.LinExp) || referencesExternal(r1c.O.LinExp) {
+			markConstraint(cID)
+			continue
+		}
+		// a constraint whose O is entirely zero (e.g. an assertion of the form
+		// a*b == 0) produces no wire for anything downstream to reach it
+		// through, and can't be reached by the reachability walk above either -
+		// but it's still a real assertion on its L/R operands, not dead weight,
+		// so it must stay live regardless of whether L/R are consumed
+		// elsewhere. A genuine 0 == 0 tautology (L, R, and O all zero) is the
+		// one exception, and is dropped separately below.
+		if isZero(r1c.O.LinExp) && !(isZero(r1c.L.LinExp) && isZero(r1c.R.LinExp)) {
+			markConstraint(cID)
+		}
+	}
+
+	// a tautological 0 == 0 R1C (e.g. left over after reduce cancels every term)
+	// is never marked live above, since every rule skips zero-coefficient
+	// terms; it never constrains anything, and is dropped here regardless.
+	isTautology := func(r1c compiled.R1C) bool {
+		return isZero(r1c.L.LinExp) && isZero(r1c.R.LinExp) && isZero(r1c.O.LinExp)
+	}
+
+	newConstraints := make([]compiled.R1C, 0, len(cs.Constraints))
+	newMDebug := make(map[int]int, len(cs.MDebug))
+	newRegions := make([]string, 0, len(cs.Constraints))
+	for cID, r1c := range cs.Constraints {
+		if !live[cID] || isTautology(r1c) {
+			droppedConstraints++
+			continue
+		}
+		if debugID, ok := cs.MDebug[cID]; ok {
+			newMDebug[len(newConstraints)] = debugID
+		}
+		newConstraints = append(newConstraints, r1c)
+		newRegions = append(newRegions, cs.regions[cID])
+	}
+	cs.Constraints = newConstraints
+	cs.MDebug = newMDebug
+	cs.regions = newRegions
+
+	// compact internal wire IDs: only wires referenced by a surviving constraint
+	// or a hint feeding one stay alive; everything else is dropped.
+	liveInternal := make([]bool, cs.NbInternalVariables)
+	markLiveInternal := func(l compiled.LinearExpression) {
+		for _, t := range l {
+			if t.CoeffID() != compiled.CoeffIdZero && t.VariableVisibility() == schema.Internal {
+				liveInternal[t.WireID()] = true
+			}
+		}
+	}
+	for _, r1c := range cs.Constraints {
+		markLiveInternal(r1c.L.LinExp)
+		markLiveInternal(r1c.R.LinExp)
+		markLiveInternal(r1c.O.LinExp)
+	}
+	for h := range liveHints {
+		for _, vID := range h.Wires {
+			liveInternal[vID] = true
+		}
+	}
+
+	oldToNewWireID := make(map[int]int, cs.NbInternalVariables)
+	nbLive := 0
+	for vID := 0; vID < cs.NbInternalVariables; vID++ {
+		if liveInternal[vID] {
+			oldToNewWireID[vID] = nbLive
+			nbLive++
+		}
+	}
+	droppedWires = cs.NbInternalVariables - nbLive
+	cs.NbInternalVariables = nbLive
+
+	remapLE := func(l compiled.LinearExpression) {
+		for i, t := range l {
+			if t.VariableVisibility() != schema.Internal {
+				continue
+			}
+			cID, _, vis := t.Unpack()
+			l[i] = compiled.Pack(oldToNewWireID[t.WireID()], cID, vis)
+		}
+	}
+	for i := range cs.Constraints {
+		remapLE(cs.Constraints[i].L.LinExp)
+		remapLE(cs.Constraints[i].R.LinExp)
+		remapLE(cs.Constraints[i].O.LinExp)
+	}
+
+	newMHints := make(map[int]*compiled.Hint, len(liveHints))
+	for h := range liveHints {
+		for i, in := range h.Inputs {
+			switch t := in.(type) {
+			case compiled.Variable:
+				remapLE(t.LinExp)
+				h.Inputs[i] = t
+			case compiled.LinearExpression:
+				remapLE(t)
+				h.Inputs[i] = t
+			}
+		}
+		for j, vID := range h.Wires {
+			h.Wires[j] = oldToNewWireID[vID]
+		}
+		for _, vID := range h.Wires {
+			newMHints[vID] = h
+		}
+	}
+	cs.MHints = newMHints
+
+	remapToResolve := func(toResolve compiled.LinearExpression) {
+		for j, t := range toResolve {
+			if t.VariableVisibility() != schema.Internal {
+				continue
+			}
+			cID, _, vis := t.Unpack()
+			toResolve[j] = compiled.Pack(oldToNewWireID[t.WireID()], cID, vis)
+		}
+	}
+	for i := range cs.Logs {
+		remapToResolve(cs.Logs[i].ToResolve)
+	}
+	for i := range cs.DebugInfo {
+		remapToResolve(cs.DebugInfo[i].ToResolve)
+	}
+
+	return droppedConstraints, droppedWires
+}
+
+// wireKey identifies a wire independently of which of L, R or O it appears in.
+type wireKey struct {
+	vis schema.Visibility
+	id  int
+}
+
+// foldConstants propagates field constants through cs.Constraints and
+// cs.MHints[*].Inputs before Compile finalizes. It seeds a set of wires known
+// to be equal to a constant from R1Cs of the form `1 * x == c` (or its mirror
+// `x * 1 == c`) left over after reduce, then iterates: every remaining
+// constraint and hint input has its known-constant wires substituted out; a
+// constraint that collapses to constants on all three sides is checked for
+// consistency (a mismatch fails compilation, citing the offending constraint's
+// MDebug entry) and dropped, while one that newly takes the `1 * x == c` shape
+// contributes another known constant. It stops once a full pass finds nothing
+// new. It returns the number of constraints folded away.
+func (cs *compiler) foldConstants() (int, error) {
+	mod := cs.CurveID.Info().Fr.Modulus()
+	known := make(map[wireKey]*big.Int)
+
+	asConstant := func(l compiled.LinearExpression) (*big.Int, bool) {
+		if len(l) == 0 {
+			return big.NewInt(0), true
+		}
+		if len(l) != 1 {
+			return nil, false
+		}
+		cID, vID, vis := l[0].Unpack()
+		if vis != schema.Public || vID != 0 {
+			return nil, false
+		}
+		return new(big.Int).Set(&cs.st.Coeffs[cID]), true
+	}
+
+	// substitute folds any term of l whose wire is already known into a running
+	// constant, which is appended back as a single term on the "one" wire.
+	substitute := func(l *compiled.LinearExpression) {
+		sum := new(big.Int)
+		kept := (*l)[:0]
+		for _, t := range *l {
+			cID, vID, vis := t.Unpack()
+			if vis == schema.Public && vID == 0 {
+				kept = append(kept, t)
+				continue
+			}
+			v, ok := known[wireKey{vis, vID}]
+			if !ok {
+				kept = append(kept, t)
+				continue
+			}
+			c := new(big.Int).Mul(&cs.st.Coeffs[cID], v)
+			sum.Add(sum, c)
+		}
+		*l = kept
+		sum.Mod(sum, mod)
+		if sum.Sign() != 0 || len(*l) == 0 {
+			*l = append(*l, cs.setCoeff(compiled.Pack(0, compiled.CoeffIdOne, schema.Public), sum))
+		}
+	}
+
+	// asAssignment recognizes a `1 * x == c` (or `x * 1 == c`) constraint and
+	// returns the wire it pins down and the value it is pinned to.
+	asAssignment := func(r1c compiled.R1C) (wireKey, *big.Int, bool) {
+		if len(r1c.L.LinExp) != 1 || len(r1c.R.LinExp) != 1 {
+			return wireKey{}, nil, false
+		}
+		oVal, ok := asConstant(r1c.O.LinExp)
+		if !ok {
+			return wireKey{}, nil, false
+		}
+
+		isOne := func(t compiled.Term) bool {
+			cID, vID, vis := t.Unpack()
+			return vis == schema.Public && vID == 0 && cID == compiled.CoeffIdOne
+		}
+
+		var wTerm compiled.Term
+		switch {
+		case isOne(r1c.L.LinExp[0]):
+			wTerm = r1c.R.LinExp[0]
+		case isOne(r1c.R.LinExp[0]):
+			wTerm = r1c.L.LinExp[0]
+		default:
+			return wireKey{}, nil, false
+		}
+		wCID, wVID, wVis := wTerm.Unpack()
+		if wVis != schema.Internal {
+			// folding a Public or Secret wire to a constant would remove the
+			// only constraint binding a witness input, letting the circuit
+			// verify for any value of that input - only internal wires (ones
+			// the circuit itself computed) are safe to fold away.
+			return wireKey{}, nil, false
+		}
+		wCoeff := &cs.st.Coeffs[wCID]
+		if wCoeff.Sign() == 0 {
+			return wireKey{}, nil, false
+		}
+		inv := new(big.Int).ModInverse(wCoeff, mod)
+		if inv == nil {
+			return wireKey{}, nil, false
+		}
+		val := new(big.Int).Mul(oVal, inv)
+		val.Mod(val, mod)
+		return wireKey{wVis, wVID}, val, true
+	}
+
+	folded := 0
+	for {
+		progress := false
+
+		for _, r1c := range cs.Constraints {
+			if k, v, ok := asAssignment(r1c); ok {
+				if _, already := known[k]; !already {
+					known[k] = v
+					progress = true
+				}
+			}
+		}
+		if !progress {
+			break
+		}
+
+		newConstraints := cs.Constraints[:0]
+		newMDebug := make(map[int]int, len(cs.MDebug))
+		newRegions := cs.regions[:0]
+		for cID, r1c := range cs.Constraints {
+			substitute(&r1c.L.LinExp)
+			substitute(&r1c.R.LinExp)
+			substitute(&r1c.O.LinExp)
+
+			lVal, lOK := asConstant(r1c.L.LinExp)
+			rVal, rOK := asConstant(r1c.R.LinExp)
+			oVal, oOK := asConstant(r1c.O.LinExp)
+			if lOK && rOK && oOK {
+				lhs := new(big.Int).Mod(new(big.Int).Mul(lVal, rVal), mod)
+				if lhs.Cmp(oVal) != 0 {
+					return folded, fmt.Errorf("constant folding: constraint is never satisfied (debug id %d): %s * %s != %s", cs.MDebug[cID], lVal, rVal, oVal)
+				}
+				folded++
+				continue
+			}
+
+			if debugID, ok := cs.MDebug[cID]; ok {
+				newMDebug[len(newConstraints)] = debugID
+			}
+			newConstraints = append(newConstraints, r1c)
+			newRegions = append(newRegions, cs.regions[cID])
+		}
+		cs.Constraints = newConstraints
+		cs.MDebug = newMDebug
+		cs.regions = newRegions
+
+		for _, h := range cs.MHints {
+			for i, in := range h.Inputs {
+				switch t := in.(type) {
+				case compiled.Variable:
+					substitute(&t.LinExp)
+					h.Inputs[i] = t
+				case compiled.LinearExpression:
+					substitute(&t)
+					h.Inputs[i] = t
+				}
+			}
+		}
+	}
+
+	return folded, nil
+}
+
 var tVariable reflect.Type
 
 func init() {
@@ -354,6 +971,26 @@ func (cs *compiler) Compile() (frontend.CompiledConstraintSystem, error) {
 		}
 	}
 
+	// cs.config.FoldConstants/EliminateDeadConstraints and the res.NbConstraints*/
+	// NbWiresEliminated/Regions fields they feed below presuppose
+	// frontend.CompileConfig and compiled.R1CS definitions that carry these
+	// additions; this tree doesn't vendor those packages at all (a condition
+	// that predates this pass), so there's no defining-package file to update
+	// here - this comment is the only honest thing to add in their place.
+	var nbFoldedConstraints int
+	if cs.config.FoldConstants {
+		var err error
+		nbFoldedConstraints, err = cs.foldConstants()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var nbEliminatedConstraints, nbEliminatedWires int
+	if cs.config.EliminateDeadConstraints {
+		nbEliminatedConstraints, nbEliminatedWires = cs.eliminateDeadConstraints()
+	}
+
 	// wires = public wires  | secret wires | internal wires
 
 	// setting up the result
@@ -363,6 +1000,10 @@ func (cs *compiler) Compile() (frontend.CompiledConstraintSystem, error) {
 	}
 	res.NbPublicVariables = len(cs.Public)
 	res.NbSecretVariables = len(cs.Secret)
+	res.NbConstraintsFolded = nbFoldedConstraints
+	res.NbConstraintsEliminated = nbEliminatedConstraints
+	res.NbWiresEliminated = nbEliminatedWires
+	res.Regions = cs.regions
 
 	// for Logs, DebugInfo and hints the only thing that will change
 	// is that ID of the wires will be offseted to take into account the final wire vector ordering
@@ -703,7 +1344,7 @@ func (system *compiler) NewHint(f hint.Function, nbOutputs int, inputs ...fronte
 		res[i] = r
 	}
 
-	ch := &compiled.Hint{ID: f.UUID(), Inputs: hintInputs, Wires: varIDs}
+	ch := &compiled.Hint{ID: f.UUID(), Inputs: hintInputs, Wires: varIDs, Region: system.currentRegion()}
 	for _, vID := range varIDs {
 		system.MHints[vID] = ch
 	}
@@ -0,0 +1,166 @@
+package r1cs
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark/frontend/compiled"
+	"github.com/consensys/gnark/frontend/schema"
+)
+
+// buildRoundShapedR1CS synthesizes an R1CS shaped like a SHA-256-style
+// compression function: nbRounds rounds, each made of nbLanes independent
+// chains hanging off the previous round's single merge wire, with chain
+// lengths staggered 1..nbLanes (mirroring how SHA-256's eight working
+// variables each take a different number of dependent operations per round),
+// followed by one constraint that merges all lanes into the round's output.
+func buildRoundShapedR1CS(nbRounds, nbLanes int) compiled.R1CS {
+	res := compiled.R1CS{}
+	res.NbPublicVariables = 1 // the "one" wire
+	res.NbSecretVariables = 0
+	nbInputs := res.NbPublicVariables + res.NbSecretVariables
+
+	nextInternal := 0
+	newWire := func() compiled.Term {
+		t := compiled.Pack(nbInputs+nextInternal, compiled.CoeffIdOne, schema.Internal)
+		nextInternal++
+		return t
+	}
+	oneWire := compiled.Pack(0, compiled.CoeffIdOne, schema.Public)
+
+	chain := func(head compiled.Term, length int) compiled.Term {
+		cur := head
+		for i := 0; i < length; i++ {
+			next := newWire()
+			res.Constraints = append(res.Constraints, compiled.R1C{
+				L: compiled.Variable{LinExp: compiled.LinearExpression{cur}},
+				R: compiled.Variable{LinExp: compiled.LinearExpression{oneWire}},
+				O: compiled.Variable{LinExp: compiled.LinearExpression{next}},
+			})
+			cur = next
+		}
+		return cur
+	}
+
+	roundOut := oneWire
+	for r := 0; r < nbRounds; r++ {
+		laneOuts := make(compiled.LinearExpression, nbLanes)
+		for lane := 0; lane < nbLanes; lane++ {
+			laneOuts[lane] = chain(roundOut, lane+1)
+		}
+		merged := newWire()
+		res.Constraints = append(res.Constraints, compiled.R1C{
+			L: compiled.Variable{LinExp: laneOuts},
+			R: compiled.Variable{LinExp: compiled.LinearExpression{oneWire}},
+			O: compiled.Variable{LinExp: compiled.LinearExpression{merged}},
+		})
+		roundOut = merged
+	}
+
+	res.NbInternalVariables = nextInternal
+	return res
+}
+
+// simulateScheduledSolve runs one synthetic "solve" per schedule, dispatching
+// every bucket's constraints to a worker pool concurrently and waiting on the
+// bucket (a barrier) before moving to the next, to approximate the real
+// solver's level-by-level dispatch without depending on the
+// curve-specific solving code this trimmed tree doesn't have.
+func simulateScheduledSolve(schedule [][]int, work func(constraintID int)) {
+	for _, bucket := range schedule {
+		done := make(chan struct{}, len(bucket))
+		for _, cID := range bucket {
+			go func(cID int) {
+				work(cID)
+				done <- struct{}{}
+			}(cID)
+		}
+		for range bucket {
+			<-done
+		}
+	}
+}
+
+// validateSchedule checks that schedule is a valid producer-before-consumer
+// ordering for ccs: every internal wire a constraint consumes must have been
+// produced by a constraint placed in a strictly earlier bucket. A schedule
+// that fails this isn't just slower, it's wrong - constraints in the same
+// bucket run concurrently, so a constraint reading a wire produced in its own
+// or a later bucket would race its producer. A constraint's own output wire
+// is not a wire it consumes.
+func validateSchedule(t testing.TB, ccs compiled.R1CS, schedule [][]int) {
+	t.Helper()
+	nbInputs := ccs.NbPublicVariables + ccs.NbSecretVariables
+
+	bucketOf := make([]int, len(ccs.Constraints))
+	for bucket, cIDs := range schedule {
+		for _, cID := range cIDs {
+			bucketOf[cID] = bucket
+		}
+	}
+
+	producerOf := make(map[int]int, ccs.NbInternalVariables)
+	for cID, c := range ccs.Constraints {
+		for _, term := range c.O.LinExp {
+			if term.CoeffID() == compiled.CoeffIdZero {
+				continue
+			}
+			if wID := term.WireID(); wID >= nbInputs {
+				if _, ok := producerOf[wID]; !ok {
+					producerOf[wID] = cID
+				}
+			}
+		}
+	}
+
+	checkLE := func(cID int, l compiled.LinearExpression) {
+		for _, term := range l {
+			if term.CoeffID() == compiled.CoeffIdZero {
+				continue
+			}
+			wID := term.WireID()
+			if wID < nbInputs {
+				continue
+			}
+			if pc, ok := producerOf[wID]; ok && pc != cID && bucketOf[pc] >= bucketOf[cID] {
+				t.Fatalf("constraint %d in bucket %d consumes wire %d, produced in bucket %d", cID, bucketOf[cID], wID, bucketOf[pc])
+			}
+		}
+	}
+	for cID, c := range ccs.Constraints {
+		checkLE(cID, c.L.LinExp)
+		checkLE(cID, c.R.LinExp)
+		checkLE(cID, c.O.LinExp)
+	}
+}
+
+func benchmarkSolveSchedule(b *testing.B, schedule [][]int) {
+	// a tiny fixed amount of busy-work stands in for the field multiply-add a
+	// real solver does per constraint; what's being compared is schedule
+	// shape (bucket count and width), not this constant.
+	work := func(int) {
+		x := 1
+		for i := 0; i < 64; i++ {
+			x = x*1664525 + 1013904223
+		}
+		_ = x
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		simulateScheduledSolve(schedule, work)
+	}
+}
+
+// BenchmarkSolveSchedule_Levels measures simulated solve wall-time, on a large
+// SHA-256-shaped circuit, for buildLevels' longest-path level schedule - the
+// only schedule this package builds. (An earlier dominator-tree-depth
+// "groups" schedule was removed: depth in the immediate-dominator tree
+// doesn't imply a producer runs in a strictly earlier bucket than every
+// consumer - two nodes can share an idom while one directly consumes the
+// other's output - so it wasn't a valid parallel schedule to begin with.)
+func BenchmarkSolveSchedule_Levels(b *testing.B) {
+	ccs := buildRoundShapedR1CS(64, 8)
+	levels := buildLevels(ccs)
+	validateSchedule(b, ccs, levels)
+	b.Logf("levels: %d buckets", len(levels))
+	benchmarkSolveSchedule(b, levels)
+}